@@ -0,0 +1,222 @@
+package kuiper
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+func cacheKey(source string, inputs []string) string {
+	return source + "\x00" + strings.Join(inputs, "\x00")
+}
+
+// cacheEntry is the LRU payload for a single (source, inputs) key. The
+// underlying cgo handle is not safe for concurrent use, so concurrent
+// callers are handed distinct compiled clones pulled from pool instead
+// of sharing one handle; clones are tracked in clones so Dispose can
+// always reclaim the native memory behind them, even ones sitting idle
+// in the pool or currently checked out.
+type cacheEntry struct {
+	key    string
+	source string
+	inputs []string
+
+	pool sync.Pool
+
+	mu     sync.Mutex
+	clones []*KuiperExpression
+
+	refs    int32
+	evicted bool
+}
+
+func newCacheEntry(key, source string, inputs []string) (*cacheEntry, error) {
+	expr, err := NewKuiperExpression(source, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &cacheEntry{key: key, source: source, inputs: inputs, clones: []*KuiperExpression{expr}, refs: 1}
+	e.pool.Put(expr)
+	return e, nil
+}
+
+func (e *cacheEntry) acquire() (*KuiperExpression, error) {
+	if v := e.pool.Get(); v != nil {
+		return v.(*KuiperExpression), nil
+	}
+
+	expr, err := NewKuiperExpression(e.source, e.inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.clones = append(e.clones, expr)
+	e.mu.Unlock()
+
+	return expr, nil
+}
+
+func (e *cacheEntry) release(expr *KuiperExpression) {
+	e.pool.Put(expr)
+}
+
+func (e *cacheEntry) dispose() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, expr := range e.clones {
+		expr.Dispose()
+	}
+	e.clones = nil
+}
+
+// CachedExpression is a handle to a compiled expression held by an
+// ExpressionCache. It must be released with Release once the caller is
+// done with it.
+type CachedExpression struct {
+	cache *ExpressionCache
+	entry *cacheEntry
+}
+
+// Run evaluates the cached expression. It is safe to call concurrently
+// from multiple goroutines and from multiple CachedExpression handles
+// sharing the same cache entry: each call borrows a compiled clone from
+// a sync.Pool for the duration of the call instead of sharing one handle.
+func (c *CachedExpression) Run(inputs ...string) (string, error) {
+	expr, err := c.entry.acquire()
+	if err != nil {
+		return "", err
+	}
+	defer c.entry.release(expr)
+
+	return expr.Run(inputs...)
+}
+
+// Release decrements the entry's reference count, allowing the cache to
+// dispose of it once evicted and no longer in flight. Call it exactly
+// once per Get, typically via defer.
+func (c *CachedExpression) Release() {
+	c.cache.release(c.entry)
+}
+
+// ExpressionCache compiles KuiperExpressions on demand and reuses them
+// across calls keyed by (source, inputs), evicting the least recently
+// used entry once the cache is full. Entries with calls in flight are
+// never freed, even after they're evicted from the LRU order.
+type ExpressionCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// NewExpressionCache creates a cache that holds at most capacity
+// compiled expressions.
+func NewExpressionCache(capacity int) *ExpressionCache {
+	return &ExpressionCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns a CachedExpression for source/inputs, compiling it if it
+// isn't already cached. The returned handle must be released with
+// Release once the caller is done with it.
+func (c *ExpressionCache) Get(source string, inputs []string) (*CachedExpression, error) {
+	key := cacheKey(source, inputs)
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		entry := el.Value.(*cacheEntry)
+		atomic.AddInt32(&entry.refs, 1)
+		c.mu.Unlock()
+		return &CachedExpression{cache: c, entry: entry}, nil
+	}
+	c.mu.Unlock()
+
+	entry, err := newCacheEntry(key, source, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		// Lost a race with another goroutine compiling the same key;
+		// use its entry and drop the one just compiled.
+		c.order.MoveToFront(el)
+		existing := el.Value.(*cacheEntry)
+		atomic.AddInt32(&existing.refs, 1)
+		entry.dispose()
+		return &CachedExpression{cache: c, entry: existing}, nil
+	}
+
+	el := c.order.PushFront(entry)
+	c.entries[key] = el
+	c.evictLocked()
+
+	return &CachedExpression{cache: c, entry: entry}, nil
+}
+
+func (c *ExpressionCache) release(entry *cacheEntry) {
+	// The decrement and the evicted/refs check must happen under the
+	// same critical section as Get's resurrect (refs 0->1) and
+	// evictLocked's evicted=true, or a concurrent Get can resurrect the
+	// entry in the window between an unguarded decrement and a later,
+	// separate read of evicted — disposing it out from under the new
+	// holder.
+	c.mu.Lock()
+	refs := atomic.AddInt32(&entry.refs, -1)
+	dispose := refs == 0 && entry.evicted
+	c.mu.Unlock()
+
+	if dispose {
+		entry.dispose()
+	}
+}
+
+// evictLocked must be called with c.mu held.
+func (c *ExpressionCache) evictLocked() {
+	for c.order.Len() > c.capacity {
+		el := c.order.Back()
+		if el == nil {
+			return
+		}
+
+		entry := el.Value.(*cacheEntry)
+		c.order.Remove(el)
+		delete(c.entries, entry.key)
+
+		if atomic.LoadInt32(&entry.refs) == 0 {
+			entry.dispose()
+		} else {
+			entry.evicted = true
+		}
+	}
+}
+
+// Dispose releases all cached expressions that are not currently in
+// flight. It should be called once the cache itself is no longer
+// needed; entries still in flight are released once their last
+// CachedExpression.Release call returns.
+func (c *ExpressionCache) Dispose() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, el := range c.entries {
+		entry := el.Value.(*cacheEntry)
+		if atomic.LoadInt32(&entry.refs) == 0 {
+			entry.dispose()
+		} else {
+			entry.evicted = true
+		}
+	}
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
@@ -0,0 +1,118 @@
+package kuiper
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestExpressionCacheReusesCompiledExpression(t *testing.T) {
+	cache := NewExpressionCache(8)
+	defer cache.Dispose()
+
+	first, err := cache.Get("in1 + in2", []string{"in1", "in2"})
+	if err != nil {
+		t.Fatalf("Failed to get cached expression: %v", err)
+	}
+	defer first.Release()
+
+	second, err := cache.Get("in1 + in2", []string{"in1", "in2"})
+	if err != nil {
+		t.Fatalf("Failed to get cached expression: %v", err)
+	}
+	defer second.Release()
+
+	if first.entry != second.entry {
+		t.Error("Expected the same cache entry for identical source/inputs")
+	}
+}
+
+func TestExpressionCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewExpressionCache(1)
+	defer cache.Dispose()
+
+	a, err := cache.Get("1 + 1", []string{})
+	if err != nil {
+		t.Fatalf("Failed to get cached expression: %v", err)
+	}
+	a.Release()
+
+	b, err := cache.Get("2 + 2", []string{})
+	if err != nil {
+		t.Fatalf("Failed to get cached expression: %v", err)
+	}
+	defer b.Release()
+
+	if len(cache.entries) != 1 {
+		t.Errorf("Expected 1 entry after eviction, got %d", len(cache.entries))
+	}
+
+	result, err := b.Run()
+	if err != nil {
+		t.Fatalf("Failed to run expression: %v", err)
+	}
+	if strings.TrimSpace(result) != "4" {
+		t.Errorf("Expected result '4', got '%s'", result)
+	}
+}
+
+func TestExpressionCacheConcurrentRun(t *testing.T) {
+	cache := NewExpressionCache(4)
+	defer cache.Dispose()
+
+	cached, err := cache.Get("in1 + in2", []string{"in1", "in2"})
+	if err != nil {
+		t.Fatalf("Failed to get cached expression: %v", err)
+	}
+	defer cached.Release()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cached.Run("1", "2"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Concurrent Run failed: %v", err)
+	}
+}
+
+func BenchmarkNaiveCompilePerCall(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		expr, err := NewKuiperExpression("in1 + in2", []string{"in1", "in2"})
+		if err != nil {
+			b.Fatalf("Failed to create expression: %v", err)
+		}
+		if _, err := expr.Run("1", "2"); err != nil {
+			b.Fatalf("Failed to run expression: %v", err)
+		}
+		expr.Dispose()
+	}
+}
+
+func BenchmarkExpressionCache(b *testing.B) {
+	cache := NewExpressionCache(8)
+	defer cache.Dispose()
+
+	b.RunParallel(func(pb *testing.PB) {
+		cached, err := cache.Get("in1 + in2", []string{"in1", "in2"})
+		if err != nil {
+			b.Fatalf("Failed to get cached expression: %v", err)
+		}
+		defer cached.Release()
+
+		for pb.Next() {
+			if _, err := cached.Run("1", "2"); err != nil {
+				b.Fatalf("Failed to run expression: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package kuiper
+
+/*
+#include <stdint.h>
+
+extern void set_expression_limits(void* expression, int64_t max_steps, int64_t max_memory_bytes, int64_t max_output_bytes);
+extern void cancel_expression(void* expression);
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// CompileOptions configures resource limits enforced while a compiled
+// expression runs, so a runaway user-supplied expression can't pin a
+// goroutine or exhaust memory when Kuiper is embedded in a server. A
+// zero value leaves the corresponding limit disabled.
+type CompileOptions struct {
+	MaxSteps       int64
+	MaxMemoryBytes int64
+	MaxOutputBytes int64
+}
+
+// NewKuiperExpressionWithOptions compiles expression like
+// NewKuiperExpression, then applies opts' resource limits before it is
+// ever run.
+func NewKuiperExpressionWithOptions(expression string, inputs []string, opts CompileOptions) (*KuiperExpression, error) {
+	expr, err := NewKuiperExpression(expression, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	C.set_expression_limits(expr.ptr, C.int64_t(opts.MaxSteps), C.int64_t(opts.MaxMemoryBytes), C.int64_t(opts.MaxOutputBytes))
+
+	return expr, nil
+}
+
+// RunContext runs the expression like Run, but aborts early if ctx is
+// canceled or its deadline expires. A watcher goroutine calls
+// cancel_expression on the underlying handle when ctx.Done() fires; the
+// Rust interpreter polls for that between evaluation steps, so Run
+// still returns once the interpreter observes the cancellation rather
+// than abandoning the call outright. RunContext waits for the watcher to
+// exit before returning, so the caller can safely Dispose the expression
+// immediately after: cancel_expression is never called on a handle that
+// may already be freed.
+func (ke *KuiperExpression) RunContext(ctx context.Context, inputs ...string) (string, error) {
+	if ke.ptr == nil {
+		return "", errors.New("expression is nil")
+	}
+
+	done := make(chan struct{})
+	var canceled atomic.Bool
+	var watcher sync.WaitGroup
+	watcher.Add(1)
+
+	go func() {
+		defer watcher.Done()
+		select {
+		case <-ctx.Done():
+			canceled.Store(true)
+			C.cancel_expression(ke.ptr)
+		case <-done:
+		}
+	}()
+
+	result, err := ke.Run(inputs...)
+	close(done)
+	watcher.Wait()
+
+	// Run erroring and our watcher having fired are only correlated, not
+	// causal — ctx's deadline can coincide with an unrelated interpreter
+	// error. Never discard that error; join ctx.Err() in alongside it so
+	// callers can still detect cancellation via errors.Is without losing
+	// the original failure.
+	if err != nil && canceled.Load() {
+		return "", errors.Join(err, ctx.Err())
+	}
+	return result, err
+}
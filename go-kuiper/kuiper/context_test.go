@@ -0,0 +1,69 @@
+package kuiper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunContextSucceeds(t *testing.T) {
+	expr, err := NewKuiperExpression("1 + 1", []string{})
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	result, err := expr.RunContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to run expression: %v", err)
+	}
+	if strings.TrimSpace(result) != "2" {
+		t.Errorf("Expected result '2', got '%s'", result)
+	}
+}
+
+func TestRunContextCanceled(t *testing.T) {
+	expr, err := NewKuiperExpression("1 + 1", []string{})
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Canceling before the watcher goroutine observes ctx.Done() is a
+	// race against how quickly the interpreter polls for cancellation;
+	// a trivial expression may still complete first. Either outcome is
+	// acceptable as long as any error RunContext returns is detectable
+	// as a cancellation via errors.Is, without losing whatever the
+	// interpreter itself reported.
+	if _, err := expr.RunContext(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected nil or an error wrapping context.Canceled, got %v", err)
+	}
+}
+
+func TestNewKuiperExpressionWithOptions(t *testing.T) {
+	expr, err := NewKuiperExpressionWithOptions("1 + 1", []string{}, CompileOptions{
+		MaxSteps:       1000,
+		MaxMemoryBytes: 1 << 20,
+		MaxOutputBytes: 1 << 10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	result, err := expr.Run()
+	if err != nil {
+		t.Fatalf("Failed to run expression: %v", err)
+	}
+	if strings.TrimSpace(result) != "2" {
+		t.Errorf("Expected result '2', got '%s'", result)
+	}
+}
@@ -0,0 +1,186 @@
+package kuiper
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include <stdbool.h>
+
+typedef struct {
+    int severity;
+    const char* code;
+    const char* message;
+    uint64_t start;
+    uint64_t end;
+    uint64_t line;
+    uint64_t col;
+} CDiagnostic;
+
+typedef struct {
+    CDiagnostic* items;
+    size_t len;
+} CDiagnosticList;
+
+extern CDiagnosticList* compile_expression_diagnostics(const char* data, const char** inputs, size_t len);
+extern void destroy_diagnostic_list(CDiagnosticList* list);
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// Severity classifies a Diagnostic.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityNote
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityNote:
+		return "note"
+	default:
+		return "unknown"
+	}
+}
+
+// Span identifies a byte range in an expression's source, along with
+// its 1-based line and column for display.
+type Span struct {
+	Start uint64
+	End   uint64
+	Line  uint64
+	Col   uint64
+}
+
+// Diagnostic is a single compiler message tied to a span of the source
+// expression, in the style of rustc/expr diagnostics.
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Message  string
+	Span     Span
+
+	// Related points at other spans relevant to the diagnostic, e.g.
+	// where a conflicting identifier was first defined.
+	Related []Span
+}
+
+// Render produces a caret-underlined snippet of source pointing at the
+// diagnostic's span, e.g.:
+//
+//	error[E0007]: Unrecognized function: notafunc
+//	 --> 1:8
+//	  |
+//	1 | "test".notafunc()
+//	  |        ^^^^^^^^^^
+func (d Diagnostic) Render(source string) string {
+	lines := strings.Split(source, "\n")
+	var lineText string
+	if d.Span.Line >= 1 && int(d.Span.Line) <= len(lines) {
+		lineText = lines[d.Span.Line-1]
+	}
+
+	// Col and End/Start come straight off the FFI for diagnostics
+	// produced by CompileDiagnostics, so treat them as untrusted: a
+	// 0/unknown column or an End before Start must not turn a render
+	// into a panic.
+	col := d.Span.Col
+	if col < 1 {
+		col = 1
+	}
+
+	width := 1
+	if d.Span.End > d.Span.Start {
+		width = int(d.Span.End - d.Span.Start)
+	}
+
+	lineNo := fmt.Sprintf("%d", d.Span.Line)
+	gutter := strings.Repeat(" ", len(lineNo))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s", d.Severity)
+	if d.Code != "" {
+		fmt.Fprintf(&b, "[%s]", d.Code)
+	}
+	fmt.Fprintf(&b, ": %s\n", d.Message)
+	fmt.Fprintf(&b, "%s--> %d:%d\n", gutter, d.Span.Line, col)
+	fmt.Fprintf(&b, "%s |\n", gutter)
+	fmt.Fprintf(&b, "%s | %s\n", lineNo, lineText)
+	fmt.Fprintf(&b, "%s | %s%s", gutter, strings.Repeat(" ", int(col)-1), strings.Repeat("^", width))
+
+	for _, related := range d.Related {
+		fmt.Fprintf(&b, "\n%s--> related at %d:%d", gutter, related.Line, related.Col)
+	}
+
+	return b.String()
+}
+
+// lineCol converts a byte offset into source to a 1-based line and
+// column.
+func lineCol(source string, offset uint64) (line, col uint64) {
+	line, col = 1, 1
+	for i := 0; i < len(source) && uint64(i) < offset; i++ {
+		if source[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+// CompileDiagnostics compiles expression like NewKuiperExpression, but
+// returns every diagnostic produced instead of aborting after the first
+// error, so IDE integrations and CLI users can see all problems in a
+// single pass.
+func CompileDiagnostics(expression string, inputs []string) ([]Diagnostic, error) {
+	cExpr := C.CString(expression)
+	defer C.free(unsafe.Pointer(cExpr))
+
+	cInputs := make([]*C.char, len(inputs))
+	for i, input := range inputs {
+		cInputs[i] = C.CString(input)
+		defer C.free(unsafe.Pointer(cInputs[i]))
+	}
+
+	var list *C.CDiagnosticList
+	if len(inputs) > 0 {
+		list = C.compile_expression_diagnostics(cExpr, (**C.char)(unsafe.Pointer(&cInputs[0])), C.size_t(len(inputs)))
+	} else {
+		list = C.compile_expression_diagnostics(cExpr, nil, 0)
+	}
+	if list == nil {
+		return nil, errors.New("failed to compile expression")
+	}
+	defer C.destroy_diagnostic_list(list)
+
+	items := unsafe.Slice(list.items, int(list.len))
+	diagnostics := make([]Diagnostic, len(items))
+	for i, item := range items {
+		diagnostics[i] = Diagnostic{
+			Severity: Severity(item.severity),
+			Code:     C.GoString(item.code),
+			Message:  C.GoString(item.message),
+			Span: Span{
+				Start: uint64(item.start),
+				End:   uint64(item.end),
+				Line:  uint64(item.line),
+				Col:   uint64(item.col),
+			},
+		}
+	}
+
+	return diagnostics, nil
+}
@@ -0,0 +1,90 @@
+package kuiper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticRender(t *testing.T) {
+	d := Diagnostic{
+		Severity: SeverityError,
+		Code:     "E0007",
+		Message:  "Unrecognized function: notafunc",
+		Span:     Span{Start: 7, End: 17, Line: 1, Col: 8},
+	}
+
+	rendered := d.Render(`"test".notafunc()`)
+
+	wantSubstrings := []string{
+		"error[E0007]: Unrecognized function: notafunc",
+		"--> 1:8",
+		`1 | "test".notafunc()`,
+	}
+	for _, want := range wantSubstrings {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Expected rendered diagnostic to contain %q, got:\n%s", want, rendered)
+		}
+	}
+
+	if got := strings.Count(rendered, "^"); got != 10 {
+		t.Errorf("Expected 10 carets (end - start), got %d", got)
+	}
+}
+
+func TestDiagnosticRenderHandlesUntrustedSpans(t *testing.T) {
+	cases := []Diagnostic{
+		{Severity: SeverityError, Message: "zero column", Span: Span{Start: 0, End: 3, Line: 1, Col: 0}},
+		{Severity: SeverityError, Message: "end before start", Span: Span{Start: 10, End: 3, Line: 1, Col: 1}},
+	}
+
+	for _, d := range cases {
+		// Must not panic regardless of what a malformed FFI span contains.
+		d.Render("abcdef")
+	}
+}
+
+func TestLineCol(t *testing.T) {
+	source := "abc\ndef\nghi"
+
+	cases := []struct {
+		offset   uint64
+		wantLine uint64
+		wantCol  uint64
+	}{
+		{0, 1, 1},
+		{3, 1, 4},
+		{4, 2, 1},
+		{9, 3, 2},
+	}
+
+	for _, c := range cases {
+		line, col := lineCol(source, c.offset)
+		if line != c.wantLine || col != c.wantCol {
+			t.Errorf("lineCol(%q, %d) = (%d, %d), want (%d, %d)", source, c.offset, line, col, c.wantLine, c.wantCol)
+		}
+	}
+}
+
+func TestKuiperCompileErrDiagnostics(t *testing.T) {
+	_, err := NewKuiperExpression("\"test\".notafunc()", []string{})
+	if err == nil {
+		t.Fatal("Expected an error, but got nil")
+	}
+
+	kuiperErr, ok := err.(*KuiperException)
+	if !ok {
+		t.Fatalf("Expected error of type *KuiperException, got %T", err)
+	}
+
+	if len(kuiperErr.Diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d", len(kuiperErr.Diagnostics))
+	}
+
+	d := kuiperErr.Diagnostics[0]
+	if d.Severity != SeverityError {
+		t.Errorf("Expected SeverityError, got %v", d.Severity)
+	}
+	if d.Span.Start != kuiperErr.Start || d.Span.End != kuiperErr.End {
+		t.Errorf("Expected diagnostic span to match exception Start/End, got %+v", d.Span)
+	}
+}
@@ -31,21 +31,40 @@ extern void destroy_expression(void* expression);
 */
 import "C"
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"unsafe"
 )
 
+// KuiperException reports a compilation failure. Message, Start, and End
+// describe the error for callers that don't need more; Diagnostics
+// carries the same error with its line and column. NewKuiperExpression
+// reports only the first compilation error; CompileDiagnostics returns
+// all of them.
 type KuiperException struct {
-	Message string
-	Start   uint64
-	End     uint64
+	Message     string
+	Start       uint64
+	End         uint64
+	Diagnostics []Diagnostic
 }
 
 func (e *KuiperException) Error() string {
 	return e.Message
 }
 
+// Render produces a caret-underlined snippet of source for every
+// diagnostic in e, in the style of rustc/expr.
+func (e *KuiperException) Render(source string) string {
+	parts := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		parts[i] = d.Render(source)
+	}
+	return strings.Join(parts, "\n")
+}
+
 type KuiperExpression struct {
 	ptr unsafe.Pointer
 }
@@ -72,10 +91,20 @@ func NewKuiperExpression(expression string, inputs []string) (*KuiperExpression,
 	}
 
 	if result.error.is_error {
+		message := C.GoString(result.error.error)
+		start := uint64(result.error.start)
+		end := uint64(result.error.end)
+		line, col := lineCol(expression, start)
+
 		return nil, &KuiperException{
-			Message: C.GoString(result.error.error),
-			Start:   uint64(result.error.start),
-			End:     uint64(result.error.end),
+			Message: message,
+			Start:   start,
+			End:     end,
+			Diagnostics: []Diagnostic{{
+				Severity: SeverityError,
+				Message:  message,
+				Span:     Span{Start: start, End: end, Line: line, Col: col},
+			}},
 		}
 	}
 
@@ -118,6 +147,61 @@ func (ke *KuiperExpression) Run(inputs ...string) (string, error) {
 	return C.GoString(result.result), nil
 }
 
+// RunJSON runs the expression against already-encoded JSON inputs and
+// returns its raw JSON output, without the marshal/unmarshal round trip
+// RunValues performs for Go values.
+func (ke *KuiperExpression) RunJSON(inputs ...json.RawMessage) (json.RawMessage, error) {
+	strs := make([]string, len(inputs))
+	for i, input := range inputs {
+		strs[i] = string(input)
+	}
+
+	result, err := ke.Run(strs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(result), nil
+}
+
+// RunValues marshals each Go value to JSON, runs the expression, and
+// unmarshals the result into an any, using json.Number so large or
+// fractional numbers round-trip without loss of precision.
+func (ke *KuiperExpression) RunValues(inputs ...any) (any, error) {
+	var out any
+	if err := ke.RunInto(&out, inputs...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RunInto runs the expression and decodes its result into dst, using
+// json.Number so large or fractional numbers round-trip without loss of
+// precision. dst should be a pointer, as with json.Unmarshal.
+func (ke *KuiperExpression) RunInto(dst any, inputs ...any) error {
+	raw := make([]json.RawMessage, len(inputs))
+	for i, input := range inputs {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return fmt.Errorf("failed to marshal input %d: %w", i, err)
+		}
+		raw[i] = data
+	}
+
+	result, err := ke.RunJSON(raw...)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(result))
+	dec.UseNumber()
+	if err := dec.Decode(dst); err != nil {
+		return fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+
+	return nil
+}
+
 func (ke *KuiperExpression) String() string {
 	if ke.ptr == nil {
 		return ""
@@ -1,6 +1,7 @@
 package kuiper
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -66,3 +67,43 @@ func TestKuiperWithInputs(t *testing.T) {
 		t.Errorf("Expected result '6', got '%s'", result)
 	}
 }
+
+func TestKuiperRunValues(t *testing.T) {
+	expr, err := NewKuiperExpression("in1 + in2.test", []string{"in1", "in2"})
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	result, err := expr.RunValues(1, map[string]int{"test": 2})
+	if err != nil {
+		t.Fatalf("Failed to run expression: %v", err)
+	}
+
+	num, ok := result.(json.Number)
+	if !ok {
+		t.Fatalf("Expected result of type json.Number, got %T", result)
+	}
+	if num.String() != "3" {
+		t.Errorf("Expected result '3', got '%s'", num.String())
+	}
+}
+
+func TestKuiperRunInto(t *testing.T) {
+	expr, err := NewKuiperExpression("in1.map(item => item.name)", []string{"in1"})
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	input := []map[string]string{{"name": "a"}, {"name": "b"}}
+
+	var names []string
+	if err := expr.RunInto(&names, input); err != nil {
+		t.Fatalf("Failed to run expression: %v", err)
+	}
+
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("Expected [a b], got %v", names)
+	}
+}
@@ -0,0 +1,196 @@
+package kuiper
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include <stdbool.h>
+
+typedef char* (*host_fn_callback)(uintptr_t handle, const char** args, size_t len, bool* out_is_error);
+
+extern void* new_function_registry();
+extern void destroy_function_registry(void* registry);
+extern bool register_host_function(void* registry, const char* name, int64_t arity, uintptr_t handle, host_fn_callback callback);
+
+extern void* compile_expression_with_registry(const char* data, const char** inputs, size_t len, void* registry);
+
+// Forward declaration of the //export'd Go trampoline below: cgo
+// generates its C signature into _cgo_export.h, which this preamble
+// does not see, so it must be redeclared here to be referenced by name.
+extern char* goHostFunctionCallback(uintptr_t handle, char** args, size_t len, bool* out_is_error);
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// HostFunction is a Go closure that a compiled Kuiper expression can
+// invoke by name. args are the function's call-site arguments decoded
+// from JSON; the returned value is marshaled back to JSON for the
+// expression to consume.
+type HostFunction func(args []any) (any, error)
+
+var (
+	callbackMu     sync.Mutex
+	callbackNextID uint64
+	callbacks      = make(map[uint64]HostFunction)
+)
+
+func registerCallback(fn HostFunction) uint64 {
+	callbackMu.Lock()
+	defer callbackMu.Unlock()
+	callbackNextID++
+	id := callbackNextID
+	callbacks[id] = fn
+	return id
+}
+
+func lookupCallback(id uint64) (HostFunction, bool) {
+	callbackMu.Lock()
+	defer callbackMu.Unlock()
+	fn, ok := callbacks[id]
+	return fn, ok
+}
+
+func unregisterCallback(id uint64) {
+	callbackMu.Lock()
+	defer callbackMu.Unlock()
+	delete(callbacks, id)
+}
+
+// Registry holds Go functions exposed to compiled Kuiper expressions
+// under a name, for use with NewKuiperExpressionWithRegistry.
+type Registry struct {
+	ptr     unsafe.Pointer
+	mu      sync.Mutex
+	handles []uint64
+}
+
+// NewRegistry creates an empty function registry.
+func NewRegistry() *Registry {
+	return &Registry{ptr: C.new_function_registry()}
+}
+
+// RegisterFunction exposes fn to expressions compiled against this
+// registry under name, callable with exactly arity arguments.
+func (r *Registry) RegisterFunction(name string, arity int, fn HostFunction) error {
+	if r.ptr == nil {
+		return errors.New("registry is nil")
+	}
+
+	handle := registerCallback(fn)
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	ok := C.register_host_function(r.ptr, cName, C.int64_t(arity), C.uintptr_t(handle), C.host_fn_callback(C.goHostFunctionCallback))
+	if !bool(ok) {
+		unregisterCallback(handle)
+		return fmt.Errorf("failed to register host function %q", name)
+	}
+
+	r.mu.Lock()
+	r.handles = append(r.handles, handle)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Dispose releases the registry and unregisters all functions added to
+// it. The registry must not be used with any expression still in use
+// once disposed.
+func (r *Registry) Dispose() {
+	if r.ptr != nil {
+		C.destroy_function_registry(r.ptr)
+		r.ptr = nil
+	}
+
+	r.mu.Lock()
+	handles := r.handles
+	r.handles = nil
+	r.mu.Unlock()
+
+	for _, handle := range handles {
+		unregisterCallback(handle)
+	}
+}
+
+// NewKuiperExpressionWithRegistry compiles expression like
+// NewKuiperExpression, but additionally makes every function in
+// registry callable from within the expression. A compilation failure is
+// reported as a plain error, not as a *KuiperException.
+func NewKuiperExpressionWithRegistry(expression string, inputs []string, registry *Registry) (*KuiperExpression, error) {
+	if registry == nil || registry.ptr == nil {
+		return nil, errors.New("registry is nil")
+	}
+
+	cExpr := C.CString(expression)
+	defer C.free(unsafe.Pointer(cExpr))
+
+	cInputs := make([]*C.char, len(inputs))
+	for i, input := range inputs {
+		cInputs[i] = C.CString(input)
+		defer C.free(unsafe.Pointer(cInputs[i]))
+	}
+
+	var ptr unsafe.Pointer
+	if len(inputs) > 0 {
+		ptr = C.compile_expression_with_registry(cExpr, (**C.char)(unsafe.Pointer(&cInputs[0])), C.size_t(len(inputs)), registry.ptr)
+	} else {
+		ptr = C.compile_expression_with_registry(cExpr, nil, 0, registry.ptr)
+	}
+	if ptr == nil {
+		return nil, fmt.Errorf("failed to compile expression")
+	}
+
+	return &KuiperExpression{ptr: ptr}, nil
+}
+
+// goHostFunctionCallback is the trampoline the Rust interpreter invokes
+// when an expression calls a registered host function. handle identifies
+// which registered HostFunction to run; args are its call-site
+// arguments, each a JSON-encoded value.
+//
+//export goHostFunctionCallback
+func goHostFunctionCallback(handle C.uintptr_t, args **C.char, length C.size_t, outIsError *C.bool) (result *C.char) {
+	defer func() {
+		if r := recover(); r != nil {
+			*outIsError = true
+			result = C.CString(fmt.Sprintf("host function panicked: %v", r))
+		}
+	}()
+
+	fn, ok := lookupCallback(uint64(handle))
+	if !ok {
+		*outIsError = true
+		return C.CString("host function not found")
+	}
+
+	cArgs := unsafe.Slice(args, int(length))
+	decoded := make([]any, len(cArgs))
+	for i, cArg := range cArgs {
+		if err := json.Unmarshal([]byte(C.GoString(cArg)), &decoded[i]); err != nil {
+			*outIsError = true
+			return C.CString(fmt.Sprintf("failed to decode argument %d: %v", i, err))
+		}
+	}
+
+	value, err := fn(decoded)
+	if err != nil {
+		*outIsError = true
+		return C.CString(err.Error())
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		*outIsError = true
+		return C.CString(fmt.Sprintf("failed to encode result: %v", err))
+	}
+
+	*outIsError = false
+	return C.CString(string(data))
+}
@@ -0,0 +1,70 @@
+package kuiper
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryRegisterFunction(t *testing.T) {
+	registry := NewRegistry()
+	defer registry.Dispose()
+
+	if err := registry.RegisterFunction("now", 0, func(args []any) (any, error) {
+		return time.Now().UTC().Format(time.RFC3339), nil
+	}); err != nil {
+		t.Fatalf("Failed to register now(): %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	if err := registry.RegisterFunction("httpGet", 1, func(args []any) (any, error) {
+		url, _ := args[0].(string)
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return string(body), nil
+	}); err != nil {
+		t.Fatalf("Failed to register httpGet(): %v", err)
+	}
+
+	lookupTable := map[string]string{"greeting": "hello"}
+	if err := registry.RegisterFunction("lookup", 1, func(args []any) (any, error) {
+		key, _ := args[0].(string)
+		return lookupTable[key], nil
+	}); err != nil {
+		t.Fatalf("Failed to register lookup(): %v", err)
+	}
+
+	expr, err := NewKuiperExpressionWithRegistry(`lookup("greeting") + " " + httpGet("`+server.URL+`") + " " + now()`, []string{}, registry)
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	result, err := expr.Run()
+	if err != nil {
+		t.Fatalf("Failed to run expression: %v", err)
+	}
+
+	result = strings.Trim(strings.TrimSpace(result), `"`)
+	prefix := "hello pong "
+	if !strings.HasPrefix(result, prefix) {
+		t.Fatalf("Expected result to start with %q, got %q", prefix, result)
+	}
+	if _, err := time.Parse(time.RFC3339, strings.TrimPrefix(result, prefix)); err != nil {
+		t.Errorf("Expected now() suffix to be RFC3339, got %q: %v", result, err)
+	}
+}
@@ -0,0 +1,151 @@
+package kuiper
+
+/*
+#include <stdint.h>
+#include <stdbool.h>
+
+typedef struct {
+    bool is_error;
+    const char* error;
+} StreamError;
+
+typedef int (*stream_pull_fn)(uintptr_t handle, uint8_t* buf, size_t buf_len, size_t* out_len);
+typedef bool (*stream_push_fn)(uintptr_t handle, const uint8_t* data, size_t len);
+
+extern void* new_expression_stream(void* expression, uintptr_t handle, stream_pull_fn pull, stream_push_fn push);
+extern StreamError run_expression_stream(void* stream);
+extern void destroy_expression_stream(void* stream);
+
+// Forward declarations of the //export'd Go trampolines below: cgo
+// generates their C signatures into _cgo_export.h, which this preamble
+// does not see, so they must be redeclared here to be referenced by name.
+extern int goStreamPull(uintptr_t handle, uint8_t* buf, size_t buf_len, size_t* out_len);
+extern bool goStreamPush(uintptr_t handle, uint8_t* data, size_t len);
+*/
+import "C"
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+)
+
+// KuiperExpressionStream evaluates a compiled expression against an
+// io.Reader and yields its output incrementally, so map/filter pipelines
+// over large JSON arrays run in bounded memory instead of buffering the
+// whole input and output.
+type KuiperExpressionStream struct {
+	expr   *KuiperExpression
+	handle uint64
+	reader io.Reader
+	writer *io.PipeWriter
+}
+
+var (
+	streamMu      sync.Mutex
+	streamNextID  uint64
+	streamEntries = make(map[uint64]*KuiperExpressionStream)
+)
+
+// NewKuiperExpressionStream prepares expr, already compiled via
+// NewKuiperExpression, for streaming evaluation.
+func NewKuiperExpressionStream(expr *KuiperExpression) (*KuiperExpressionStream, error) {
+	if expr == nil || expr.ptr == nil {
+		return nil, errors.New("expression is nil")
+	}
+	return &KuiperExpressionStream{expr: expr}, nil
+}
+
+// RunReader incrementally feeds JSON tokens read from r across the FFI
+// boundary and returns an io.Reader that yields the expression's output
+// as it is produced. The returned reader is an *io.PipeReader; it must
+// be fully drained, or closed, to release the goroutine driving the
+// evaluation.
+func (s *KuiperExpressionStream) RunReader(r io.Reader) (io.Reader, error) {
+	pr, pw := io.Pipe()
+	s.reader = r
+	s.writer = pw
+
+	streamMu.Lock()
+	streamNextID++
+	s.handle = streamNextID
+	streamEntries[s.handle] = s
+	streamMu.Unlock()
+
+	go s.run(pw)
+
+	return pr, nil
+}
+
+func (s *KuiperExpressionStream) run(pw *io.PipeWriter) {
+	defer func() {
+		streamMu.Lock()
+		delete(streamEntries, s.handle)
+		streamMu.Unlock()
+	}()
+
+	stream := C.new_expression_stream(s.expr.ptr, C.uintptr_t(s.handle), C.stream_pull_fn(C.goStreamPull), C.stream_push_fn(C.goStreamPush))
+	if stream == nil {
+		pw.CloseWithError(errors.New("failed to create expression stream"))
+		return
+	}
+	defer C.destroy_expression_stream(stream)
+
+	result := C.run_expression_stream(stream)
+	if bool(result.is_error) {
+		pw.CloseWithError(errors.New(C.GoString(result.error)))
+		return
+	}
+	pw.Close()
+}
+
+// goStreamPull is called by the Rust interpreter to pull up to bufLen
+// bytes of input. It returns 1 with outLen set on a successful read, 0
+// on EOF, and -1 on a read error.
+//
+//export goStreamPull
+func goStreamPull(handle C.uintptr_t, buf *C.uint8_t, bufLen C.size_t, outLen *C.size_t) C.int {
+	streamMu.Lock()
+	s, ok := streamEntries[uint64(handle)]
+	streamMu.Unlock()
+	if !ok {
+		return -1
+	}
+
+	dst := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(bufLen))
+	n, err := s.reader.Read(dst)
+	*outLen = C.size_t(n)
+	// io.Reader permits n > 0 with err == io.EOF in the same call; those
+	// bytes are still valid and must be reported as a successful read,
+	// with the EOF surfacing on the next, zero-byte Read.
+	if n > 0 {
+		return 1
+	}
+	if err != nil {
+		if err == io.EOF {
+			return 0
+		}
+		return -1
+	}
+	return 1
+}
+
+// goStreamPush is called by the Rust interpreter to push a chunk of
+// produced output. It returns false if the consumer has stopped reading.
+//
+//export goStreamPush
+func goStreamPush(handle C.uintptr_t, data *C.uint8_t, length C.size_t) C.bool {
+	streamMu.Lock()
+	s, ok := streamEntries[uint64(handle)]
+	streamMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	src := unsafe.Slice((*byte)(unsafe.Pointer(data)), int(length))
+	if _, err := s.writer.Write(src); err != nil {
+		return false
+	}
+	return true
+}
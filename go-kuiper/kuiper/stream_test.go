@@ -0,0 +1,39 @@
+package kuiper
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestKuiperExpressionStreamNilExpression(t *testing.T) {
+	if _, err := NewKuiperExpressionStream(nil); err == nil {
+		t.Fatal("Expected an error for a nil expression, but got nil")
+	}
+}
+
+func TestKuiperExpressionStreamRunReader(t *testing.T) {
+	expr, err := NewKuiperExpression("input.map(item => item * 2)", []string{"input"})
+	if err != nil {
+		t.Fatalf("Failed to create expression: %v", err)
+	}
+	defer expr.Dispose()
+
+	stream, err := NewKuiperExpressionStream(expr)
+	if err != nil {
+		t.Fatalf("Failed to create expression stream: %v", err)
+	}
+
+	r, err := stream.RunReader(strings.NewReader("[1, 2, 3]"))
+	if err != nil {
+		t.Fatalf("Failed to run stream: %v", err)
+	}
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read stream output: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "[2,4,6]" {
+		t.Errorf("Expected '[2,4,6]', got '%s'", out)
+	}
+}
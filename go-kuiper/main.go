@@ -18,15 +18,15 @@ func main() {
 	defer expr.Dispose()
 
 	// Apply the expression to some data
-	data1 := "1"
-	data2 := `{"test": 2}`
-	fmt.Printf("Running Kuiper expression with data: %s, %s\n", data1, data2)
-	result, err := expr.Run(data1, data2)
+	data1 := 1
+	data2 := map[string]int{"test": 2}
+	fmt.Printf("Running Kuiper expression with data: %v, %v\n", data1, data2)
+	result, err := expr.RunValues(data1, data2)
 	if err != nil {
 		log.Fatalf("Failed to run expression: %v", err)
 	}
 
-	fmt.Printf("Result: %s\n", result)
+	fmt.Printf("Result: %v\n", result)
 
 	// Test with a GraphQL response
 	jsonData := `{